@@ -1,38 +1,80 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+
 	"github.com/HarshvardhanPandey2003/FluxEngine/internal/handler"
-	"github.com/HarshvardhanPandey2003/FluxEngine/internal/models"
+	"github.com/HarshvardhanPandey2003/FluxEngine/internal/ratelimit"
+	"github.com/HarshvardhanPandey2003/FluxEngine/internal/store"
 	"github.com/HarshvardhanPandey2003/FluxEngine/internal/worker"
 )
 
+const (
+	defaultWorkerCount     = 4
+	defaultQueueCapacity   = 1000
+	defaultRateLimitRPS    = 5.0
+	defaultRateLimitBurst  = 20
+	defaultShutdownTimeout = 30 * time.Second
+	defaultDBBackend       = "memory"
+	defaultSQLiteDSN       = "fluxengine.db"
+)
+
 func main() {
-	// Phase 1: Single unbuffered channel for job communication and we use 
-	// This is the main entry point(Starting point for accepting the requests) for the FluxEngine server application.
-	jobQueue := make(chan *models.Job)
+	workerCount := envInt("WORKER_COUNT", defaultWorkerCount)
+	queueCapacity := envInt("QUEUE_CAPACITY", defaultQueueCapacity)
+	shutdownTimeout := envDuration("SHUTDOWN_TIMEOUT", defaultShutdownTimeout)
+
+	// Phase 4: persist job status/results so they survive past the
+	// fire-and-forget /submit call. Defaults to an in-memory store;
+	// set DB_BACKEND=sqlite or DB_BACKEND=postgres (with DB_DSN) for a
+	// durable one.
+	jobStore := newJobStore()
+
+	// Phase 3: a bounded priority queue fed by N worker goroutines,
+	// replacing the Phase 1 single unbuffered channel and lone Worker.
+	// runCtx governs the pool's lifetime: cancelling it (on SIGTERM) is
+	// how a long-running bcrypt/statistics job learns to abort at its
+	// next safe checkpoint instead of being killed mid-flight.
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	defer cancelRun()
 
-	// Start a single worker goroutine
-	// This worker will perform CPU-intensive operations (bcrypt, statistics)
-	worker := worker.NewWorker(1, jobQueue) // Worker ID = 1
-	go worker.Start()
+	pool := worker.NewPool(workerCount, queueCapacity, jobStore)
+	pool.Start(runCtx)
 
-	// Initialize HTTP handler with access to the job queue
-	jobHandler := handler.NewJobHandler(jobQueue)
+	// Requeue anything a previous run left "processing", "cancelled", or
+	// "pending" when it was interrupted mid-flight. With the default
+	// in-memory store this never finds anything (there's nothing to
+	// survive a restart with); it matters once DB_BACKEND selects sqlite
+	// or postgres.
+	requeueInterrupted(pool, jobStore)
+
+	limiter := ratelimit.NewLimiter(defaultRateLimitRPS, defaultRateLimitBurst)
+
+	// Initialize HTTP handlers
+	jobHandler := handler.NewJobHandler(pool, jobStore, limiter)
+	metricsHandler := handler.NewMetricsHandler(pool)
 
 	// Route registration
-	http.HandleFunc("/health", healthCheckHandler)
+	http.HandleFunc("/health", healthCheckHandler(workerCount))
 	http.HandleFunc("/submit", jobHandler.HandleSubmitJob)
+	http.HandleFunc("/jobs", jobHandler.HandleJobsRoute)
+	http.HandleFunc("/jobs/", jobHandler.HandleJobsRoute)
+	http.HandleFunc("/metrics", metricsHandler.HandleMetrics)
 
 	// Configure HTTP server settings
-	// We use & here instead of hhtp.Server directly  
+	// We use & here instead of hhtp.Server directly
 	server := &http.Server{
 		Addr:         ":8080",
 		Handler:      nil, // Use DefaultServeMux
@@ -43,13 +85,17 @@ func main() {
 
 	// Start server in background goroutine
 	go func() {
-		log.Println("🚀 FluxEngine Phase 1: CPU-Intensive Job Processor")
+		log.Println("🚀 FluxEngine Phase 3: Multi-Worker Job Processor")
 		log.Println("📌 Server running on http://localhost:8080")
 		log.Println("📌 Endpoints:")
 		log.Println("   GET  /health           - Health check")
 		log.Println("   POST /submit           - Submit CPU-intensive job")
+		log.Println("   GET  /jobs             - List jobs (optional ?status=)")
+		log.Println("   GET  /jobs/{id}        - Job status")
+		log.Println("   GET  /jobs/{id}/result - Job result")
+		log.Println("   GET  /metrics          - Queue depth, in-flight, latency histograms")
 		log.Println("")
-		log.Println("📊 Worker initialized (1 worker, unbuffered queue)")
+		log.Printf("📊 Pool initialized (%d workers, queue capacity %d)\n", workerCount, queueCapacity)
 		log.Println("⚙️  Ready to process password hashing & report generation")
 		log.Println("")
 
@@ -64,23 +110,120 @@ func main() {
 	<-quit
 
 	log.Println("🛑 Shutdown signal received...")
-	close(jobQueue) // Signal worker to stop after finishing current job
-	time.Sleep(2 * time.Second) // Give worker time to finish (crude version)
+
+	// Stop accepting new connections, then let in-flight HTTP requests
+	// (including any /submit still writing its response) finish.
+	httpShutdownCtx, cancelHTTPShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelHTTPShutdown()
+	if err := server.Shutdown(httpShutdownCtx); err != nil {
+		log.Printf("⚠️  HTTP server shutdown error: %v", err)
+	}
+
+	// Cancel the pool's run context so in-flight jobs abort at their next
+	// safe checkpoint, then wait (up to the same deadline) for them to
+	// actually stop.
+	cancelRun()
+	pool.Shutdown(shutdownTimeout)
+
 	log.Println("✅ FluxEngine stopped")
 }
 
-// healthCheckHandler responds with server status.
-func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-
-	response := map[string]interface{}{
-		"status":  "healthy",
-		"service": "fluxengine",
-		"phase":   "1-skeleton",
-		"workers": 1,
-		"time":    time.Now().Format(time.RFC3339),
+// requeueInterrupted resubmits jobs a previous run left "processing",
+// "cancelled", or still "pending" when the process exited before they
+// finished. "pending" covers jobs a client submitted but that never made
+// it past the queue before shutdown.
+func requeueInterrupted(pool *worker.Pool, jobStore store.JobStore) {
+	for _, status := range []string{"processing", "cancelled", "pending"} {
+		for _, job := range jobStore.List(status) {
+			log.Printf("🔁 Requeuing job %s left %q by a previous run", job.ID, status)
+			if err := pool.Submit(job); err != nil {
+				log.Printf("⚠️  Failed to requeue job %s: %v", job.ID, err)
+			}
+		}
+	}
+}
+
+// newJobStore selects the persistence backend from DB_BACKEND ("memory",
+// the default; "sqlite"; or "postgres"), opening DB_DSN for the SQL
+// backends. This is what makes store.SQLStore actually reachable from a
+// running server instead of library code nothing ever calls.
+func newJobStore() store.JobStore {
+	backend := os.Getenv("DB_BACKEND")
+	if backend == "" {
+		backend = defaultDBBackend
+	}
+
+	switch backend {
+	case "memory":
+		return store.NewMemoryStore()
+	case "sqlite":
+		dsn := os.Getenv("DB_DSN")
+		if dsn == "" {
+			dsn = defaultSQLiteDSN
+		}
+		db, err := sql.Open("sqlite", dsn)
+		if err != nil {
+			log.Fatalf("❌ Failed to open sqlite store at %q: %v", dsn, err)
+		}
+		return store.NewSQLStore(db, store.DialectSQLite)
+	case "postgres":
+		dsn := os.Getenv("DB_DSN")
+		if dsn == "" {
+			log.Fatal("❌ DB_BACKEND=postgres requires DB_DSN to be set")
+		}
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			log.Fatalf("❌ Failed to open postgres store at %q: %v", dsn, err)
+		}
+		return store.NewSQLStore(db, store.DialectPostgres)
+	default:
+		log.Fatalf("❌ Unknown DB_BACKEND %q (want \"memory\", \"sqlite\", or \"postgres\")", backend)
+		return nil
+	}
+}
+
+// envInt reads an environment variable as an int, falling back to def if
+// unset or unparsable.
+func envInt(key string, def int) int {
+	val := os.Getenv(key)
+	if val == "" {
+		return def
+	}
+	parsed, err := strconv.Atoi(val)
+	if err != nil {
+		return def
+	}
+	return parsed
+}
+
+// envDuration reads an environment variable as a Go duration string
+// (e.g. "30s"), falling back to def if unset or unparsable.
+func envDuration(key string, def time.Duration) time.Duration {
+	val := os.Getenv(key)
+	if val == "" {
+		return def
+	}
+	parsed, err := time.ParseDuration(val)
+	if err != nil {
+		return def
 	}
+	return parsed
+}
 
-	json.NewEncoder(w).Encode(response)
+// healthCheckHandler responds with server status.
+func healthCheckHandler(workerCount int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		response := map[string]interface{}{
+			"status":  "healthy",
+			"service": "fluxengine",
+			"phase":   "3-worker-pool",
+			"workers": workerCount,
+			"time":    time.Now().Format(time.RFC3339),
+		}
+
+		json.NewEncoder(w).Encode(response)
+	}
 }
@@ -5,20 +5,36 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/HarshvardhanPandey2003/FluxEngine/internal/models"
+	"github.com/HarshvardhanPandey2003/FluxEngine/internal/ratelimit"
+	"github.com/HarshvardhanPandey2003/FluxEngine/internal/store"
+	"github.com/HarshvardhanPandey2003/FluxEngine/internal/worker"
 )
 
-// JobHandler manages HTTP endpoints for job submission.
+// defaultSubmitTimeout is how long HandleSubmitJob waits for queue space
+// when the request doesn't specify its own `timeout_ms`.
+const defaultSubmitTimeout = 0 // immediate reject, matching the old behavior
+
+// JobHandler manages HTTP endpoints for job submission and tracking.
 type JobHandler struct {
-	jobQueue chan<- *models.Job // Send-only channel
+	pool    *worker.Pool
+	store   store.JobStore     // Phase 4: status/result lookups
+	limiter *ratelimit.Limiter // per-client-IP submission throttle
 }
 
-// NewJobHandler initializes the handler with a job queue reference.
-func NewJobHandler(jobQueue chan<- *models.Job) *JobHandler {
+// NewJobHandler initializes the handler with the worker pool jobs are
+// submitted to, the store used to track job lifecycle and results, and a
+// rate limiter guarding /submit per client IP.
+func NewJobHandler(pool *worker.Pool, jobStore store.JobStore, limiter *ratelimit.Limiter) *JobHandler {
 	return &JobHandler{
-		jobQueue: jobQueue,
+		pool:    pool,
+		store:   jobStore,
+		limiter: limiter,
 	}
 }
 
@@ -28,6 +44,39 @@ type SubmitJobRequest struct {
 	Payload map[string]interface{} `json:"payload"` // Job-specific parameters
 }
 
+// redactedPayloadFields lists payload keys that must never be echoed
+// back over the status/list API. Job IDs are a predictable timestamp
+// plus 6 weak pseudo-random characters, not a secret, so anyone who can
+// see or guess one must not be able to read a submitted plaintext
+// password back out of it.
+var redactedPayloadFields = map[string]bool{
+	"password": true,
+}
+
+const redactedPlaceholder = "[redacted]"
+
+// redactedJob returns a copy of job with sensitive payload fields masked,
+// safe to serialize for GET /jobs and GET /jobs/{id}.
+func redactedJob(job *models.Job) *models.Job {
+	redacted := job.Clone()
+	for key := range redacted.Payload {
+		if redactedPayloadFields[key] {
+			redacted.Payload[key] = redactedPlaceholder
+		}
+	}
+	return redacted
+}
+
+// clientIP extracts the requester's IP for rate limiting, stripping the
+// port from RemoteAddr.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 // HandleSubmitJob processes POST requests to enqueue CPU-intensive background jobs.
 func (h *JobHandler) HandleSubmitJob(w http.ResponseWriter, r *http.Request) {
 	// Only accept POST requests
@@ -36,6 +85,12 @@ func (h *JobHandler) HandleSubmitJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.limiter != nil && !h.limiter.Allow(clientIP(r)) {
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "Rate limit exceeded, slow down", http.StatusTooManyRequests)
+		return
+	}
+
 	// Read and parse request body
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -52,40 +107,53 @@ func (h *JobHandler) HandleSubmitJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate job type
-	if req.Type != "password_hash" && req.Type != "report_generation" {
-		http.Error(w, "Invalid job type. Use 'password_hash' or 'report_generation'", http.StatusBadRequest)
+	// Validate job type against the registry instead of a hard-coded list,
+	// so a new worker.Register call is all it takes to support it here too.
+	jobHandlerImpl, ok := worker.Lookup(req.Type)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Invalid job type %q. Registered types: %v", req.Type, worker.RegisteredTypes()), http.StatusBadRequest)
+		return
+	}
+	if req.Payload == nil {
+		req.Payload = make(map[string]interface{})
+	}
+	if err := jobHandlerImpl.Validate(req.Payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Validate required payload fields based on job type
-	if req.Type == "password_hash" {
-		if _, ok := req.Payload["password"]; !ok {
-			http.Error(w, "Missing 'password' field in payload", http.StatusBadRequest)
+	// Create job instance
+	job := models.NewJob(req.Type, req.Payload)
+
+	if priorityVal, ok := req.Payload["priority"].(float64); ok {
+		job.Priority = int(priorityVal)
+	}
+
+	// Phase 4: register the job before handing it to the pool, so a
+	// GET /jobs/{id} issued immediately after submission already finds it
+	// (status "pending").
+	if h.store != nil {
+		if err := h.store.Save(job); err != nil {
+			log.Printf("❌ Failed to persist job %s: %v", job.ID, err)
+			http.Error(w, "Failed to record job", http.StatusInternalServerError)
 			return
 		}
-	} else if req.Type == "report_generation" {
-		if _, ok := req.Payload["data_points"]; !ok {
-			// Default to 1 million data points if not specified
-			req.Payload["data_points"] = 1000000
-		}
 	}
 
-	// Create job instance
-	job := models.NewJob(req.Type, req.Payload)
+	// timeout_ms lets a caller wait briefly for queue space instead of
+	// being rejected the instant the pool is saturated.
+	submitTimeout := time.Duration(defaultSubmitTimeout) * time.Millisecond
+	if timeoutVal, ok := req.Payload["timeout_ms"].(float64); ok && timeoutVal > 0 {
+		submitTimeout = time.Duration(timeoutVal) * time.Millisecond
+	}
 
-	// NON-BLOCKING SEND: Use select with default to avoid hanging
-	// If the worker is busy and channel is full, we reject immediately
-	select {
-	case h.jobQueue <- job:
-		// Job successfully queued
-		log.Printf("✅ Job %s accepted (type: %s)", job.ID, job.Type)
-	default:
-		// Worker busy or queue full (in Phase 1, this shouldn't happen often)
-		log.Printf("⚠️  Job queue full, rejecting job")
+	if err := h.pool.SubmitWithTimeout(job, submitTimeout); err != nil {
+		log.Printf("⚠️  Job queue saturated, rejecting job %s: %v", job.ID, err)
+		w.Header().Set("Retry-After", "2")
 		http.Error(w, "System overloaded, try again later", http.StatusServiceUnavailable)
 		return
 	}
+	log.Printf("✅ Job %s accepted (type: %s, priority: %d)", job.ID, job.Type, job.Priority)
 
 	// IMMEDIATE RESPONSE: Client doesn't wait for job completion
 	w.Header().Set("Content-Type", "application/json")
@@ -95,8 +163,118 @@ func (h *JobHandler) HandleSubmitJob(w http.ResponseWriter, r *http.Request) {
 		"status":  "accepted",
 		"job_id":  job.ID,
 		"message": fmt.Sprintf("Job %s queued for CPU-intensive processing", job.ID),
-		"note":    "Job will be processed asynchronously. Use job_id to track status (future phases).",
+		"note":    "Use GET /jobs/{id} to track status and GET /jobs/{id}/result for the result.",
 	}
 
 	json.NewEncoder(w).Encode(response)
 }
+
+// HandleGetJob serves GET /jobs/{id}, returning the job's current status.
+func (h *JobHandler) HandleGetJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/jobs/")
+	if id == "" {
+		http.Error(w, "Missing job id", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.store.Get(id)
+	if err != nil {
+		if err == store.ErrNotFound {
+			http.Error(w, "Job not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to look up job", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(redactedJob(job))
+}
+
+// HandleGetJobResult serves GET /jobs/{id}/result, returning the computed
+// JobResult once the job has completed (or failed).
+func (h *JobHandler) HandleGetJobResult(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/jobs/"), "/result")
+	if id == "" {
+		http.Error(w, "Missing job id", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.store.Get(id)
+	if err != nil {
+		if err == store.ErrNotFound {
+			http.Error(w, "Job not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to look up job", http.StatusInternalServerError)
+		return
+	}
+	if job.Status != "completed" && job.Status != "failed" {
+		http.Error(w, fmt.Sprintf("Job %s is still %s", id, job.Status), http.StatusConflict)
+		return
+	}
+
+	result, err := h.store.GetResult(id)
+	if err != nil {
+		if err == store.ErrNotFound {
+			http.Error(w, "Result not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to look up result", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// HandleListJobs serves GET /jobs?status=..., listing jobs optionally
+// filtered by status. An empty or missing status returns every job.
+func (h *JobHandler) HandleListJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	jobs := h.store.List(status)
+
+	redactedJobs := make([]*models.Job, len(jobs))
+	for i, job := range jobs {
+		redactedJobs[i] = redactedJob(job)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"count": len(redactedJobs),
+		"jobs":  redactedJobs,
+	})
+}
+
+// HandleJobsRoute dispatches GET /jobs, GET /jobs/{id} and
+// GET /jobs/{id}/result. It exists because net/http's default ServeMux
+// (pre-1.22) can't express path parameters, so we split on the path shape
+// ourselves.
+func (h *JobHandler) HandleJobsRoute(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/jobs")
+	path = strings.Trim(path, "/")
+
+	switch {
+	case path == "":
+		h.HandleListJobs(w, r)
+	case strings.HasSuffix(path, "/result"):
+		h.HandleGetJobResult(w, r)
+	default:
+		h.HandleGetJob(w, r)
+	}
+}
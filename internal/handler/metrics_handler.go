@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/HarshvardhanPandey2003/FluxEngine/internal/worker"
+)
+
+// MetricsHandler serves /metrics for the worker pool.
+type MetricsHandler struct {
+	pool *worker.Pool
+}
+
+// NewMetricsHandler wires the /metrics endpoint to a pool.
+func NewMetricsHandler(pool *worker.Pool) *MetricsHandler {
+	return &MetricsHandler{pool: pool}
+}
+
+// HandleMetrics writes queue depth, in-flight count, and processing
+// latency histograms per job type in Prometheus text exposition format.
+func (h *MetricsHandler) HandleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	snap := h.pool.Metrics().Snapshot(h.pool.QueueDepth())
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	fmt.Fprintln(w, "# HELP fluxengine_queue_depth Jobs currently queued, by type.")
+	fmt.Fprintln(w, "# TYPE fluxengine_queue_depth gauge")
+	for jobType, depth := range snap.QueueDepth {
+		fmt.Fprintf(w, "fluxengine_queue_depth{type=%q} %d\n", jobType, depth)
+	}
+
+	fmt.Fprintln(w, "# HELP fluxengine_jobs_in_flight Jobs currently being processed, by type.")
+	fmt.Fprintln(w, "# TYPE fluxengine_jobs_in_flight gauge")
+	for jobType, count := range snap.InFlight {
+		fmt.Fprintf(w, "fluxengine_jobs_in_flight{type=%q} %d\n", jobType, count)
+	}
+
+	fmt.Fprintln(w, "# HELP fluxengine_jobs_enqueued_total Jobs submitted, by type.")
+	fmt.Fprintln(w, "# TYPE fluxengine_jobs_enqueued_total counter")
+	for jobType, total := range snap.EnqueuedTotal {
+		fmt.Fprintf(w, "fluxengine_jobs_enqueued_total{type=%q} %d\n", jobType, total)
+	}
+
+	fmt.Fprintln(w, "# HELP fluxengine_job_duration_seconds Processing latency histogram, by type.")
+	fmt.Fprintln(w, "# TYPE fluxengine_job_duration_seconds histogram")
+	for jobType, counts := range snap.LatencyCounts {
+		cumulative := int64(0)
+		for i, bound := range snap.LatencyBounds {
+			cumulative += counts[i]
+			fmt.Fprintf(w, "fluxengine_job_duration_seconds_bucket{type=%q,le=\"%g\"} %d\n", jobType, bound, cumulative)
+		}
+		cumulative += counts[len(snap.LatencyBounds)]
+		fmt.Fprintf(w, "fluxengine_job_duration_seconds_bucket{type=%q,le=\"+Inf\"} %d\n", jobType, cumulative)
+	}
+}
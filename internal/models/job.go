@@ -7,11 +7,13 @@ import (
 // Job represents a CPU-intensive unit of work for background processing.
 // Each job contains metadata and a payload that determines what computation to perform.
 type Job struct {
-	ID        string                 `json:"id"`         // Unique identifier
-	Type      string                 `json:"type"`       // "password_hash" or "report_generation"
-	Payload   map[string]interface{} `json:"payload"`    // Job-specific data
-	CreatedAt time.Time              `json:"created_at"` // Submission timestamp
-	Status    string                 `json:"status"`     // "pending", "processing", "completed", "failed"
+	ID         string                 `json:"id"`          // Unique identifier
+	Type       string                 `json:"type"`        // A registered job type, e.g. "password_hash", "argon2id", "pbkdf2", "report_generation"
+	Payload    map[string]interface{} `json:"payload"`     // Job-specific data
+	Priority   int                    `json:"priority"`    // Higher runs first; ties broken by EnqueuedAt
+	CreatedAt  time.Time              `json:"created_at"`  // Submission timestamp
+	EnqueuedAt time.Time              `json:"enqueued_at"` // When the job entered the pool's queue
+	Status     string                 `json:"status"`      // "pending", "processing", "completed", "failed", "cancelled"
 }
 
 // JobResult holds the output of a completed job.
@@ -23,6 +25,21 @@ type JobResult struct {
 	CompletedAt time.Time   `json:"completed_at"`
 }
 
+// Clone returns a deep copy of the job, independent of the original's
+// Payload map. Stores must return clones from Get/List so a caller
+// holding the result can't race with a worker goroutine still mutating
+// the stored job.
+func (j *Job) Clone() *Job {
+	clone := *j
+	if j.Payload != nil {
+		clone.Payload = make(map[string]interface{}, len(j.Payload))
+		for k, v := range j.Payload {
+			clone.Payload[k] = v
+		}
+	}
+	return &clone
+}
+
 // NewJob creates a new job instance with initialized fields.
 func NewJob(jobType string, payload map[string]interface{}) *Job {
 	return &Job{
@@ -0,0 +1,99 @@
+// Package ratelimit implements a per-client token-bucket limiter used to
+// protect /submit from a single noisy IP monopolizing the job queue.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// idleEvictionFactor is how many refill periods (the time to go from
+// empty to full at `rate` tokens/sec) a bucket may sit untouched before a
+// sweep reclaims it. A bucket idle that long is back at a full, fresh
+// burst anyway, so evicting it changes no caller-visible behavior — only
+// the map no longer grows without bound over the server's lifetime.
+const idleEvictionFactor = 10
+
+// sweepInterval caps how often Allow bothers scanning the whole map for
+// idle buckets, so the sweep itself can't become the cost driver on a
+// busy limiter.
+const sweepInterval = time.Minute
+
+// bucket tracks one client's remaining tokens and when it was last
+// refilled.
+type bucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// Limiter is a per-key token-bucket rate limiter. Each key (typically a
+// client IP) gets its own bucket that refills at `rate` tokens per
+// second up to `burst` tokens. Buckets idle past idleEvictionFactor
+// refill periods are swept out periodically so ordinary traffic from
+// many distinct IPs doesn't grow the bucket map without bound.
+type Limiter struct {
+	rate    float64
+	burst   float64
+	idleTTL time.Duration
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewLimiter creates a limiter allowing `rate` requests/sec per key, with
+// bursts up to `burst` requests.
+func NewLimiter(rate float64, burst int) *Limiter {
+	return &Limiter{
+		rate:      rate,
+		burst:     float64(burst),
+		idleTTL:   time.Duration(idleEvictionFactor*float64(burst)/rate) * time.Second,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow reports whether a request from key may proceed, consuming one
+// token if so.
+func (l *Limiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst - 1, lastRefill: now}
+		l.buckets[key] = b
+		return true
+	}
+
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.tokens += elapsed * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.lastRefill = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// sweep evicts buckets idle past idleTTL, at most once per sweepInterval.
+// Callers must hold l.mu.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.lastRefill) >= l.idleTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"sync"
+	"time"
+
+	"github.com/HarshvardhanPandey2003/FluxEngine/internal/models"
+)
+
+// MemoryStore is an in-process JobStore backed by a map guarded by an
+// RWMutex. It is the default backend: fast and dependency-free, but
+// results are lost on restart. Use a durable backend (SQLStore) when
+// that matters.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	jobs    map[string]*models.Job
+	results map[string]*models.JobResult
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jobs:    make(map[string]*models.Job),
+		results: make(map[string]*models.JobResult),
+	}
+}
+
+// Save registers a newly submitted job.
+func (s *MemoryStore) Save(job *models.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = job
+	return nil
+}
+
+// UpdateStatus transitions a job to a new status.
+func (s *MemoryStore) UpdateStatus(id string, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	job.Status = status
+	return nil
+}
+
+// SaveResult records the outcome of a finished job.
+func (s *MemoryStore) SaveResult(id string, result interface{}, duration time.Duration, jobErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	if jobErr != nil {
+		job.Status = "failed"
+		s.results[id] = &models.JobResult{
+			JobID:       id,
+			Duration:    duration.String(),
+			Result:      map[string]interface{}{"error": jobErr.Error()},
+			CompletedAt: time.Now(),
+		}
+		return nil
+	}
+
+	job.Status = "completed"
+	s.results[id] = &models.JobResult{
+		JobID:       id,
+		Duration:    duration.String(),
+		Result:      result,
+		CompletedAt: time.Now(),
+	}
+	return nil
+}
+
+// Get returns a job's current metadata. It returns a clone rather than
+// the stored pointer: the worker goroutine mutates the stored job's
+// fields directly (under s.mu), and handing out the live pointer would
+// let a caller's json.Marshal race with those writes.
+func (s *MemoryStore) Get(id string) (*models.Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return job.Clone(), nil
+}
+
+// GetResult returns the stored result for a completed job.
+func (s *MemoryStore) GetResult(id string) (*models.JobResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result, ok := s.results[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return result, nil
+}
+
+// List returns clones of jobs matching status, or all jobs if status is
+// empty. See Get for why clones rather than the stored pointers.
+func (s *MemoryStore) List(status string) []*models.Job {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jobs := make([]*models.Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		if status == "" || job.Status == status {
+			jobs = append(jobs, job.Clone())
+		}
+	}
+	return jobs
+}
@@ -0,0 +1,208 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/HarshvardhanPandey2003/FluxEngine/internal/models"
+)
+
+// Dialect identifies which placeholder syntax a SQLStore needs to emit.
+// database/sql doesn't rewrite placeholders itself, so the same query
+// text can't be handed as-is to every driver: SQLite and MySQL drivers
+// want positional `?` markers, while lib/pq and pgx require numbered
+// `$1, $2, ...` markers.
+type Dialect int
+
+const (
+	// DialectSQLite covers mattn/go-sqlite3 and modernc.org/sqlite, both
+	// of which accept `?` placeholders as written below.
+	DialectSQLite Dialect = iota
+	// DialectPostgres covers lib/pq and pgx, which require `$1, $2, ...`
+	// placeholders; queries are rebound before being sent.
+	DialectPostgres
+)
+
+// SQLStore is a durable JobStore backed by database/sql. It works against
+// any driver reachable through database/sql (SQLite via mattn/go-sqlite3
+// or modernc.org/sqlite, Postgres via lib/pq or pgx) — callers open the
+// *sql.DB with the driver of their choice, pass it in here along with the
+// matching Dialect, and SQLStore rebinds its `?`-style queries to that
+// driver's placeholder syntax. Job payloads and results are stored as
+// JSON text so the schema doesn't need to know about job types.
+//
+// Schema (SQLite/Postgres compatible):
+//
+//	CREATE TABLE jobs (
+//		id          TEXT PRIMARY KEY,
+//		type        TEXT NOT NULL,
+//		payload     TEXT NOT NULL,
+//		status      TEXT NOT NULL,
+//		created_at  TIMESTAMP NOT NULL
+//	);
+//	CREATE TABLE job_results (
+//		job_id       TEXT PRIMARY KEY REFERENCES jobs(id),
+//		duration     TEXT NOT NULL,
+//		result       TEXT NOT NULL,
+//		completed_at TIMESTAMP NOT NULL
+//	);
+type SQLStore struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewSQLStore wraps an already-open database/sql handle for the given
+// dialect. Callers are responsible for migrating the schema above before
+// first use.
+func NewSQLStore(db *sql.DB, dialect Dialect) *SQLStore {
+	return &SQLStore{db: db, dialect: dialect}
+}
+
+// rebind rewrites a query written with `?` placeholders into the target
+// dialect's syntax. SQLite passes through unchanged; Postgres gets `?`
+// replaced with `$1, $2, ...` in order, since database/sql sends query
+// text straight to the driver without touching placeholder syntax.
+func (s *SQLStore) rebind(query string) string {
+	if s.dialect != DialectPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Save registers a newly submitted job.
+func (s *SQLStore) Save(job *models.Job) error {
+	payload, err := json.Marshal(job.Payload)
+	if err != nil {
+		return fmt.Errorf("store: marshal payload: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		s.rebind(`INSERT INTO jobs (id, type, payload, status, created_at) VALUES (?, ?, ?, ?, ?)`),
+		job.ID, job.Type, string(payload), job.Status, job.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("store: save job: %w", err)
+	}
+	return nil
+}
+
+// UpdateStatus transitions a job to a new status.
+func (s *SQLStore) UpdateStatus(id string, status string) error {
+	res, err := s.db.Exec(s.rebind(`UPDATE jobs SET status = ? WHERE id = ?`), status, id)
+	if err != nil {
+		return fmt.Errorf("store: update status: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SaveResult records the outcome of a finished job.
+func (s *SQLStore) SaveResult(id string, result interface{}, duration time.Duration, jobErr error) error {
+	status := "completed"
+	payload := result
+	if jobErr != nil {
+		status = "failed"
+		payload = map[string]interface{}{"error": jobErr.Error()}
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("store: marshal result: %w", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("store: begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(s.rebind(`UPDATE jobs SET status = ? WHERE id = ?`), status, id); err != nil {
+		return fmt.Errorf("store: update status: %w", err)
+	}
+	if _, err := tx.Exec(
+		s.rebind(`INSERT INTO job_results (job_id, duration, result, completed_at) VALUES (?, ?, ?, ?)`),
+		id, duration.String(), string(encoded), time.Now(),
+	); err != nil {
+		return fmt.Errorf("store: insert result: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// Get returns a job's current metadata.
+func (s *SQLStore) Get(id string) (*models.Job, error) {
+	var job models.Job
+	var payload string
+	row := s.db.QueryRow(s.rebind(`SELECT id, type, payload, status, created_at FROM jobs WHERE id = ?`), id)
+	if err := row.Scan(&job.ID, &job.Type, &payload, &job.Status, &job.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("store: get job: %w", err)
+	}
+	if err := json.Unmarshal([]byte(payload), &job.Payload); err != nil {
+		return nil, fmt.Errorf("store: unmarshal payload: %w", err)
+	}
+	return &job, nil
+}
+
+// GetResult returns the stored result for a completed job.
+func (s *SQLStore) GetResult(id string) (*models.JobResult, error) {
+	var result models.JobResult
+	var encoded string
+	row := s.db.QueryRow(s.rebind(`SELECT job_id, duration, result, completed_at FROM job_results WHERE job_id = ?`), id)
+	if err := row.Scan(&result.JobID, &result.Duration, &encoded, &result.CompletedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("store: get result: %w", err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(encoded), &decoded); err != nil {
+		return nil, fmt.Errorf("store: unmarshal result: %w", err)
+	}
+	result.Result = decoded
+	return &result, nil
+}
+
+// List returns jobs matching status, or all jobs if status is empty.
+func (s *SQLStore) List(status string) []*models.Job {
+	var rows *sql.Rows
+	var err error
+	if status == "" {
+		rows, err = s.db.Query(`SELECT id, type, payload, status, created_at FROM jobs`)
+	} else {
+		rows, err = s.db.Query(s.rebind(`SELECT id, type, payload, status, created_at FROM jobs WHERE status = ?`), status)
+	}
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var jobs []*models.Job
+	for rows.Next() {
+		var job models.Job
+		var payload string
+		if err := rows.Scan(&job.ID, &job.Type, &payload, &job.Status, &job.CreatedAt); err != nil {
+			continue
+		}
+		_ = json.Unmarshal([]byte(payload), &job.Payload)
+		jobs = append(jobs, &job)
+	}
+	return jobs
+}
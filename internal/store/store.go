@@ -0,0 +1,45 @@
+// Package store provides the Phase 4 persistence layer for FluxEngine.
+// It lets submitted jobs outlive the in-process worker: a JobStore
+// records job metadata and results so the HTTP handler can answer
+// status/result queries after the fact, even across a server restart
+// if a durable backend is configured.
+package store
+
+import (
+	"errors"
+	"time"
+
+	"github.com/HarshvardhanPandey2003/FluxEngine/internal/models"
+)
+
+// ErrNotFound is returned when a job or result cannot be located.
+var ErrNotFound = errors.New("store: job not found")
+
+// JobStore records job lifecycle state and results. Implementations must
+// be safe for concurrent use: the HTTP handler reads from it while the
+// worker writes to it from a different goroutine.
+type JobStore interface {
+	// Save registers a newly submitted job, before it is queued for
+	// processing.
+	Save(job *models.Job) error
+
+	// UpdateStatus transitions a job to a new status (e.g. "processing",
+	// "cancelled").
+	UpdateStatus(id string, status string) error
+
+	// SaveResult records the outcome of a finished job. A non-nil jobErr
+	// marks the job "failed" and stores the error string instead of a
+	// result payload.
+	SaveResult(id string, result interface{}, duration time.Duration, jobErr error) error
+
+	// Get returns a copy of the job's current metadata, independent of
+	// whatever a worker goroutine does to the stored job afterwards.
+	Get(id string) (*models.Job, error)
+
+	// GetResult returns the stored result for a completed job.
+	GetResult(id string) (*models.JobResult, error)
+
+	// List returns copies of jobs matching status, or all jobs if status
+	// is empty.
+	List(status string) []*models.Job
+}
@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	defaultArgon2Time    = 1
+	defaultArgon2Memory  = 64 * 1024 // KiB
+	defaultArgon2Threads = 4
+	defaultArgon2KeyLen  = 32
+	argon2SaltLen        = 16
+
+	// maxArgon2Time/Memory/Threads bound what a single job may request.
+	// Unlike bcrypt's cost or pbkdf2's iteration count, argon2's ctx can't
+	// be checked mid-call, so an unbounded "memory": 4_000_000_000 or
+	// similarly huge time/threads value would OOM-kill the process or pin
+	// a worker goroutine forever instead of just running slowly.
+	maxArgon2Time    = 10
+	maxArgon2Memory  = 1 * 1024 * 1024 // 1 GiB, in KiB
+	maxArgon2Threads = 16
+)
+
+func init() {
+	Register("argon2id", &Argon2idHandler{})
+}
+
+// Argon2idHandler hashes passwords with Argon2id, the password-hashing
+// competition winner and OWASP's current recommendation. Unlike bcrypt
+// it's tunable across both CPU time and memory, which is what the
+// `time`, `memory`, `threads`, and `keyLen` payload params control.
+type Argon2idHandler struct{}
+
+// Validate checks the password field and fills in Argon2id defaults.
+func (h *Argon2idHandler) Validate(payload map[string]any) error {
+	if _, ok := payload["password"].(string); !ok {
+		return fmt.Errorf("invalid or missing 'password' field")
+	}
+
+	setDefaultUint(payload, "time", defaultArgon2Time)
+	clampUint(payload, "time", maxArgon2Time)
+	setDefaultUint(payload, "memory", defaultArgon2Memory)
+	clampUint(payload, "memory", maxArgon2Memory)
+	setDefaultUint(payload, "threads", defaultArgon2Threads)
+	clampUint(payload, "threads", maxArgon2Threads)
+	setDefaultUint(payload, "keyLen", defaultArgon2KeyLen)
+	return nil
+}
+
+// Run derives the key with Argon2id over a freshly generated random salt.
+func (h *Argon2idHandler) Run(ctx context.Context, payload map[string]any) (any, error) {
+	// argon2.IDKey can't be interrupted mid-call, so this is the only
+	// safe checkpoint available: bail before starting if shutdown is
+	// already underway.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	password := payload["password"].(string)
+	timeCost := uintParam(payload, "time", defaultArgon2Time)
+	memory := uintParam(payload, "memory", defaultArgon2Memory)
+	threads := uint8(uintParam(payload, "threads", defaultArgon2Threads))
+	keyLen := uintParam(payload, "keyLen", defaultArgon2KeyLen)
+
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	hash := argon2.IDKey([]byte(password), salt, timeCost, memory, threads, keyLen)
+
+	return map[string]interface{}{
+		"hash":      base64.RawStdEncoding.EncodeToString(hash),
+		"salt":      base64.RawStdEncoding.EncodeToString(salt),
+		"time":      timeCost,
+		"memory":    memory,
+		"threads":   threads,
+		"key_len":   keyLen,
+		"algorithm": "argon2id",
+	}, nil
+}
+
+// setDefaultUint writes def into payload[key] (as float64, matching how
+// encoding/json decodes numbers) if the key is absent or not a positive
+// number.
+func setDefaultUint(payload map[string]any, key string, def uint32) {
+	if v, ok := payload[key].(float64); ok && v > 0 {
+		return
+	}
+	payload[key] = float64(def)
+}
+
+// clampUint caps payload[key] at max, same idea as BcryptHandler clamping
+// cost into bcrypt's valid range: argon2.IDKey can't check ctx mid-call,
+// so an unbounded time/memory/threads value would OOM-kill the process
+// or pin a worker goroutine forever rather than just running slowly.
+func clampUint(payload map[string]any, key string, max uint32) {
+	if v, ok := payload[key].(float64); ok && v > float64(max) {
+		payload[key] = float64(max)
+	}
+}
+
+// uintParam reads a uint32 payload field written by setDefaultUint.
+func uintParam(payload map[string]any, key string, def uint32) uint32 {
+	if v, ok := payload[key].(float64); ok && v > 0 {
+		return uint32(v)
+	}
+	return def
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func init() {
+	Register("password_hash", &BcryptHandler{})
+}
+
+// BcryptHandler hashes passwords with bcrypt. Bcrypt is intentionally
+// slow (CPU-intensive) to defend against brute-force attacks.
+type BcryptHandler struct{}
+
+// Validate checks that a password was supplied and normalizes the cost
+// parameter into bcrypt's valid range.
+func (h *BcryptHandler) Validate(payload map[string]any) error {
+	if _, ok := payload["password"].(string); !ok {
+		return fmt.Errorf("invalid or missing 'password' field")
+	}
+
+	if costVal, ok := payload["cost"].(float64); ok {
+		cost := int(costVal)
+		if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			payload["cost"] = float64(bcrypt.DefaultCost)
+		}
+	}
+	return nil
+}
+
+// Run hashes the password and round-trips it through CompareHashAndPassword
+// as additional CPU work to demonstrate the hash actually verifies.
+func (h *BcryptHandler) Run(ctx context.Context, payload map[string]any) (any, error) {
+	// bcrypt.GenerateFromPassword can't be interrupted mid-call, so this
+	// is the only safe checkpoint available: bail before starting if
+	// shutdown is already underway.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	password := payload["password"].(string)
+
+	cost := bcrypt.DefaultCost
+	if costVal, ok := payload["cost"].(float64); ok {
+		cost = int(costVal)
+	}
+
+	// THIS IS CPU-INTENSIVE: bcrypt.GenerateFromPassword uses key stretching
+	// Cost of 10 = 2^10 iterations, cost of 12 = 2^12 iterations, etc.
+	// Higher cost = exponentially more CPU time
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return nil, fmt.Errorf("bcrypt hashing failed: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword(hashedPassword, []byte(password)); err != nil {
+		return nil, fmt.Errorf("hash verification failed: %w", err)
+	}
+
+	return map[string]interface{}{
+		"hash_length": len(hashedPassword),
+		"cost":        cost,
+		"algorithm":   "bcrypt",
+		"verified":    true,
+	}, nil
+}
@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	defaultPBKDF2Iterations = 100_000
+	defaultPBKDF2KeyLen     = 32
+	defaultPBKDF2HashAlgo   = "sha256"
+	pbkdf2SaltLen           = 16
+
+	// maxPBKDF2Iterations bounds what a single job may request. pbkdf2.Key
+	// can't check ctx mid-call, so an unbounded iteration count (e.g.
+	// "iterations": 2_000_000_000) would pin a worker goroutine forever
+	// instead of just running slowly.
+	maxPBKDF2Iterations = 10_000_000
+)
+
+func init() {
+	Register("pbkdf2", &PBKDF2Handler{})
+}
+
+// PBKDF2Handler derives keys with PBKDF2, configurable by iteration
+// count and underlying hash algorithm (sha256 or sha512).
+type PBKDF2Handler struct{}
+
+// Validate checks the password field, the hash algorithm name, and fills
+// in defaults for iterations/key length.
+func (h *PBKDF2Handler) Validate(payload map[string]any) error {
+	if _, ok := payload["password"].(string); !ok {
+		return fmt.Errorf("invalid or missing 'password' field")
+	}
+
+	algo, _ := payload["hash_algo"].(string)
+	if algo == "" {
+		payload["hash_algo"] = defaultPBKDF2HashAlgo
+	} else if _, err := pbkdf2HashFunc(algo); err != nil {
+		return err
+	}
+
+	if v, ok := payload["iterations"].(float64); !ok || v <= 0 {
+		payload["iterations"] = float64(defaultPBKDF2Iterations)
+	} else if v > maxPBKDF2Iterations {
+		payload["iterations"] = float64(maxPBKDF2Iterations)
+	}
+	if v, ok := payload["keyLen"].(float64); !ok || v <= 0 {
+		payload["keyLen"] = float64(defaultPBKDF2KeyLen)
+	}
+	return nil
+}
+
+// Run derives the key over a freshly generated random salt.
+func (h *PBKDF2Handler) Run(ctx context.Context, payload map[string]any) (any, error) {
+	// pbkdf2.Key can't be interrupted mid-call, so this is the only safe
+	// checkpoint available: bail before starting if shutdown is already
+	// underway.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	password := payload["password"].(string)
+	iterations := int(payload["iterations"].(float64))
+	keyLen := int(payload["keyLen"].(float64))
+	algo := payload["hash_algo"].(string)
+
+	newHash, err := pbkdf2HashFunc(algo)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, pbkdf2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	derivedKey := pbkdf2.Key([]byte(password), salt, iterations, keyLen, newHash)
+
+	return map[string]interface{}{
+		"hash":       base64.RawStdEncoding.EncodeToString(derivedKey),
+		"salt":       base64.RawStdEncoding.EncodeToString(salt),
+		"iterations": iterations,
+		"key_len":    keyLen,
+		"hash_algo":  algo,
+		"algorithm":  "pbkdf2",
+	}, nil
+}
+
+// pbkdf2HashFunc resolves a payload hash_algo name to the hash
+// constructor PBKDF2 expects.
+func pbkdf2HashFunc(algo string) (func() hash.Hash, error) {
+	switch algo {
+	case "sha256":
+		return sha256.New, nil
+	case "sha512":
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported hash_algo %q, use 'sha256' or 'sha512'", algo)
+	}
+}
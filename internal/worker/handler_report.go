@@ -0,0 +1,79 @@
+package worker
+
+import (
+	"context"
+	"crypto/rand"
+	"log"
+	"math/big"
+	"time"
+)
+
+func init() {
+	Register("report_generation", &ReportHandler{})
+}
+
+// ReportHandler simulates large-scale data aggregation: it generates
+// random numbers and streams them through an online statistics
+// accumulator, so neither memory nor CPU time scales with holding or
+// sorting the full dataset.
+type ReportHandler struct{}
+
+// Validate fills in the default data_points count (1,000,000), caps it
+// at 100 million, and resets a non-positive delta to the default.
+func (h *ReportHandler) Validate(payload map[string]any) error {
+	dataPoints := 1_000_000
+	if dpVal, ok := payload["data_points"].(float64); ok {
+		dataPoints = int(dpVal)
+	}
+
+	// Cap at 100 million: with streaming stats this is bounded by CPU
+	// time, not memory.
+	if dataPoints > 100_000_000 {
+		dataPoints = 100_000_000
+	}
+	payload["data_points"] = float64(dataPoints)
+
+	if deltaVal, ok := payload["delta"].(float64); ok && deltaVal <= 0 {
+		payload["delta"] = float64(defaultTDigestCompression)
+	}
+
+	return nil
+}
+
+// Run generates the dataset and computes its statistics.
+func (h *ReportHandler) Run(ctx context.Context, payload map[string]any) (any, error) {
+	dataPoints := int(payload["data_points"].(float64))
+
+	delta := float64(defaultTDigestCompression)
+	if deltaVal, ok := payload["delta"].(float64); ok && deltaVal > 0 {
+		delta = deltaVal
+	}
+
+	log.Printf("   📊 Streaming %d random values through the statistics accumulator (delta=%.0f)...", dataPoints, delta)
+
+	// Check for shutdown every N points rather than every point: ctx.Err()
+	// is cheap but not free, and this loop is the hot path for 100M-point
+	// jobs.
+	const cancelCheckInterval = 100_000
+
+	stats := NewOnlineStats(delta)
+	for i := 0; i < dataPoints; i++ {
+		if i%cancelCheckInterval == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		// Generate cryptographically secure random float between 0 and 1000
+		// This is more CPU-intensive than math/rand but demonstrates real work
+		val, err := rand.Int(rand.Reader, big.NewInt(1000))
+		if err != nil {
+			// Fallback to timestamp-based pseudo-random if crypto/rand fails
+			stats.Add(float64(time.Now().UnixNano() % 1000))
+		} else {
+			stats.Add(float64(val.Int64()))
+		}
+	}
+
+	log.Printf("   🧮 Finalizing statistics over %d values...", dataPoints)
+
+	return stats.Finalize(), nil
+}
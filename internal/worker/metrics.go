@@ -0,0 +1,105 @@
+package worker
+
+import (
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the upper bounds (in seconds) of the processing
+// latency histogram exposed on /metrics. A value lands in the first
+// bucket whose bound it doesn't exceed; anything past the last bound
+// falls into a final "+Inf" bucket.
+var latencyBuckets = []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60}
+
+// Metrics tracks per-job-type queue depth, in-flight counts, and
+// processing latency. It's written by worker goroutines and read by the
+// /metrics HTTP handler concurrently, so every access goes through mu.
+type Metrics struct {
+	mu sync.Mutex
+
+	enqueuedTotal map[string]int64
+	inFlight      map[string]int64
+	latencyCounts map[string][]int64 // per type: one counter per bucket, plus overflow
+}
+
+// NewMetrics creates an empty metrics registry.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		enqueuedTotal: make(map[string]int64),
+		inFlight:      make(map[string]int64),
+		latencyCounts: make(map[string][]int64),
+	}
+}
+
+func (m *Metrics) recordEnqueue(jobType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enqueuedTotal[jobType]++
+}
+
+func (m *Metrics) recordStart(jobType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight[jobType]++
+}
+
+func (m *Metrics) recordComplete(jobType string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight[jobType]--
+
+	counts, ok := m.latencyCounts[jobType]
+	if !ok {
+		counts = make([]int64, len(latencyBuckets)+1)
+		m.latencyCounts[jobType] = counts
+	}
+	seconds := duration.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			counts[i]++
+			return
+		}
+	}
+	counts[len(latencyBuckets)]++
+}
+
+// Snapshot is a point-in-time, lock-free copy of the metrics state.
+type Snapshot struct {
+	QueueDepth    map[string]int
+	EnqueuedTotal map[string]int64
+	InFlight      map[string]int64
+	LatencyBounds []float64
+	LatencyCounts map[string][]int64
+}
+
+// Snapshot copies the current metrics state, merging in the live queue
+// depth (which the Pool owns, not Metrics, since it's a property of the
+// heap rather than something workers record).
+func (m *Metrics) Snapshot(queueDepth map[string]int) Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	enqueued := make(map[string]int64, len(m.enqueuedTotal))
+	for k, v := range m.enqueuedTotal {
+		enqueued[k] = v
+	}
+	inFlight := make(map[string]int64, len(m.inFlight))
+	for k, v := range m.inFlight {
+		inFlight[k] = v
+	}
+	latency := make(map[string][]int64, len(m.latencyCounts))
+	for k, v := range m.latencyCounts {
+		cp := make([]int64, len(v))
+		copy(cp, v)
+		latency[k] = cp
+	}
+
+	return Snapshot{
+		QueueDepth:    queueDepth,
+		EnqueuedTotal: enqueued,
+		InFlight:      inFlight,
+		LatencyBounds: latencyBuckets,
+		LatencyCounts: latency,
+	}
+}
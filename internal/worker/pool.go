@@ -0,0 +1,269 @@
+package worker
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/HarshvardhanPandey2003/FluxEngine/internal/models"
+	"github.com/HarshvardhanPandey2003/FluxEngine/internal/store"
+)
+
+// ErrQueueFull is returned by Submit when the pool's bounded queue has no
+// room left, so the caller (the HTTP handler) can turn it into a 503.
+var ErrQueueFull = errors.New("worker: queue is saturated")
+
+// priorityItem wraps a job for ordering inside the heap.
+type priorityItem struct {
+	job   *models.Job
+	index int
+}
+
+// jobHeap orders jobs by Priority (higher first), breaking ties with
+// EnqueuedAt (earlier first) so same-priority jobs are still FIFO.
+type jobHeap []*priorityItem
+
+func (h jobHeap) Len() int { return len(h) }
+
+func (h jobHeap) Less(i, j int) bool {
+	if h[i].job.Priority != h[j].job.Priority {
+		return h[i].job.Priority > h[j].job.Priority
+	}
+	return h[i].job.EnqueuedAt.Before(h[j].job.EnqueuedAt)
+}
+
+func (h jobHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *jobHeap) Push(x any) {
+	item := x.(*priorityItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *jobHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*h = old[:n-1]
+	return item
+}
+
+// Pool runs a fixed number of worker goroutines pulling from a bounded,
+// priority-ordered queue. It replaces the Phase 1 single unbuffered
+// channel and single Worker, adding backpressure (Submit rejects once
+// `capacity` jobs are queued) and priority scheduling.
+type Pool struct {
+	size     int
+	capacity int
+	store    store.JobStore
+	metrics  *Metrics
+
+	mu      sync.Mutex
+	queue   jobHeap
+	wake    chan struct{}
+	notFull chan struct{}
+
+	dispatch chan *models.Job
+	wg       sync.WaitGroup
+}
+
+// NewPool creates a pool of `size` workers backed by a priority queue
+// bounded at `capacity` jobs.
+func NewPool(size, capacity int, jobStore store.JobStore) *Pool {
+	return &Pool{
+		size:     size,
+		capacity: capacity,
+		store:    jobStore,
+		metrics:  NewMetrics(),
+		wake:     make(chan struct{}, 1),
+		notFull:  make(chan struct{}, 1),
+		dispatch: make(chan *models.Job),
+	}
+}
+
+// Metrics exposes the pool's metrics registry for the /metrics handler.
+func (p *Pool) Metrics() *Metrics { return p.metrics }
+
+// Submit enqueues a job for processing. It returns ErrQueueFull if the
+// queue is already at capacity instead of blocking.
+func (p *Pool) Submit(job *models.Job) error {
+	p.mu.Lock()
+	if len(p.queue) >= p.capacity {
+		p.mu.Unlock()
+		return ErrQueueFull
+	}
+
+	job.EnqueuedAt = time.Now()
+	heap.Push(&p.queue, &priorityItem{job: job})
+	p.mu.Unlock()
+
+	p.metrics.recordEnqueue(job.Type)
+	p.signal(p.wake)
+	return nil
+}
+
+// SubmitWithTimeout retries Submit until it succeeds or the queue has had
+// no room for longer than timeout, in which case it returns ErrQueueFull.
+// This backs the per-job `timeout_ms` payload field: instead of the
+// instant reject Phase 1 did, callers can ask to wait briefly for space
+// to free up.
+func (p *Pool) SubmitWithTimeout(job *models.Job, timeout time.Duration) error {
+	if timeout <= 0 {
+		return p.Submit(job)
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		err := p.Submit(job)
+		if !errors.Is(err, ErrQueueFull) {
+			return err
+		}
+		select {
+		case <-p.notFull:
+		case <-deadline.C:
+			return ErrQueueFull
+		}
+	}
+}
+
+// signal performs a non-blocking send on a capacity-1 notification
+// channel, collapsing redundant wakeups instead of blocking the caller.
+func (p *Pool) signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// Start spawns the dispatcher and worker goroutines. They run until ctx
+// is cancelled: a SIGTERM cancels ctx, which unblocks every goroutine
+// waiting in dispatchLoop or runWorker at its next select, and is also
+// passed down into each job's Handler.Run so CPU-bound loops can abort
+// at their own safe checkpoints instead of running to completion.
+func (p *Pool) Start(ctx context.Context) {
+	go p.dispatchLoop(ctx)
+	for i := 1; i <= p.size; i++ {
+		p.wg.Add(1)
+		go p.runWorker(ctx, i)
+	}
+	log.Printf("🔧 Pool started with %d workers (queue capacity %d)", p.size, p.capacity)
+}
+
+// dispatchLoop pops the highest-priority job and hands it to whichever
+// worker goroutine is free to receive on the unbuffered dispatch channel.
+// On shutdown it drains whatever's left in the queue (including the item
+// it just popped, if ctx won the race against a waiting worker) and marks
+// each cancelled, so none of them are silently lost.
+func (p *Pool) dispatchLoop(ctx context.Context) {
+	for {
+		p.mu.Lock()
+		for len(p.queue) == 0 {
+			p.mu.Unlock()
+			select {
+			case <-p.wake:
+			case <-ctx.Done():
+				p.drainQueue()
+				return
+			}
+			p.mu.Lock()
+		}
+		item := heap.Pop(&p.queue).(*priorityItem)
+		p.mu.Unlock()
+
+		p.signal(p.notFull)
+
+		select {
+		case p.dispatch <- item.job:
+		case <-ctx.Done():
+			p.markCancelled(item.job)
+			p.drainQueue()
+			return
+		}
+	}
+}
+
+// drainQueue marks every job still sitting in the queue as cancelled, so
+// their status records that shutdown abandoned them; requeueInterrupted
+// picks them back up on the next boot.
+func (p *Pool) drainQueue() {
+	p.mu.Lock()
+	remaining := make([]*priorityItem, len(p.queue))
+	copy(remaining, p.queue)
+	p.queue = p.queue[:0]
+	p.mu.Unlock()
+
+	for _, item := range remaining {
+		p.markCancelled(item.job)
+	}
+}
+
+// markCancelled records that a queued job was abandoned by shutdown
+// before a worker ever picked it up. Goes through the store's locked
+// UpdateStatus only, not a direct job.Status write: job is the same
+// pointer the store holds, and an unguarded write here would race with a
+// concurrent store.Get/List call reading that field.
+func (p *Pool) markCancelled(job *models.Job) {
+	log.Printf("🛑 Pool: job %s cancelled before dispatch by shutdown", job.ID)
+	if p.store != nil {
+		if err := p.store.UpdateStatus(job.ID, "cancelled"); err != nil {
+			log.Printf("⚠️  Pool: failed to mark job %s cancelled: %v", job.ID, err)
+		}
+	}
+}
+
+func (p *Pool) runWorker(ctx context.Context, id int) {
+	defer p.wg.Done()
+	w := NewWorker(id, p.store, p.metrics)
+
+	for {
+		select {
+		case job := <-p.dispatch:
+			w.processJob(ctx, job)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Shutdown waits (up to deadline) for jobs a worker already picked up to
+// drain. Callers cancel the Start context first so workers stop pulling
+// new jobs and in-flight handlers see their checkpoints fire; Shutdown
+// only blocks on the ones already running.
+func (p *Pool) Shutdown(deadline time.Duration) {
+	waited := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(waited)
+	}()
+
+	select {
+	case <-waited:
+		log.Println("✅ Pool drained: all in-flight jobs finished")
+	case <-time.After(deadline):
+		log.Println("⚠️  Pool shutdown deadline hit with jobs still in flight")
+	}
+}
+
+// QueueDepth returns the number of queued (not yet dispatched) jobs,
+// grouped by type, for the /metrics endpoint.
+func (p *Pool) QueueDepth() map[string]int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	depth := make(map[string]int)
+	for _, item := range p.queue {
+		depth[item.job.Type]++
+	}
+	return depth
+}
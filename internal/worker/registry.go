@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Handler implements a single job type: validating its payload up front
+// and running the CPU-intensive work itself. Adding a new job type is a
+// single Register call instead of edits to the hard-coded switch that
+// used to live in processJob, the HTTP handler's validation, and the
+// submit-request docs.
+type Handler interface {
+	// Validate checks (and may fill in defaults on) the payload before
+	// the job is queued, so bad requests fail at /submit time rather
+	// than after a worker has already picked them up.
+	Validate(payload map[string]any) error
+
+	// Run performs the CPU-intensive work and returns the job result.
+	Run(ctx context.Context, payload map[string]any) (any, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Handler)
+)
+
+// Register adds a handler for the given job type. It panics on a
+// duplicate registration, which only happens from a programming error
+// (two init() funcs claiming the same type), not from user input.
+func Register(jobType string, h Handler) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[jobType]; exists {
+		panic(fmt.Sprintf("worker: job type %q already registered", jobType))
+	}
+	registry[jobType] = h
+}
+
+// Lookup returns the handler registered for jobType, if any.
+func Lookup(jobType string) (Handler, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	h, ok := registry[jobType]
+	return h, ok
+}
+
+// RegisteredTypes lists every job type currently registered, for
+// diagnostics such as the HTTP handler's invalid-type error message.
+func RegisteredTypes() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	types := make([]string, 0, len(registry))
+	for t := range registry {
+		types = append(types, t)
+	}
+	return types
+}
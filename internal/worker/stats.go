@@ -0,0 +1,213 @@
+package worker
+
+import "math"
+
+// defaultTDigestCompression bounds how many centroids a TDigest keeps
+// around; higher values trade memory for percentile accuracy.
+const defaultTDigestCompression = 100
+
+// tdigestCentroid is a weighted mean: `weight` points have been merged
+// into it, averaging out to `mean`.
+type tdigestCentroid struct {
+	mean   float64
+	weight float64
+}
+
+// TDigest is a mergeable sketch of a distribution, after Ted Dunning's
+// "Computing Extremely Accurate Quantiles Using t-Digests". It keeps a
+// small, sorted set of weighted centroids instead of every sample, so
+// p25/median/p95/p99 can be estimated from a single streaming pass over
+// an arbitrarily large dataset.
+type TDigest struct {
+	compression float64
+	centroids   []tdigestCentroid
+	count       float64
+}
+
+// NewTDigest creates an empty digest with the given compression factor.
+// A larger compression keeps more centroids (better accuracy, more
+// memory); FluxEngine defaults to 100.
+func NewTDigest(compression float64) *TDigest {
+	if compression <= 0 {
+		compression = defaultTDigestCompression
+	}
+	return &TDigest{compression: compression}
+}
+
+// maxWeight returns the largest weight a centroid sitting at cumulative
+// quantile q is allowed to carry before a new point must start its own
+// centroid instead of merging. It derives from the t-digest scale
+// function k(q) = delta/(2*pi) * asin(2q-1): centroids near the median
+// (where k(q) changes slowly) can absorb many points, while centroids
+// near the tails (where k(q) changes quickly) stay small, giving better
+// resolution exactly where percentile queries like p95/p99 need it.
+func (d *TDigest) maxWeight(q float64) float64 {
+	if q <= 0 {
+		q = 1e-9
+	}
+	if q >= 1 {
+		q = 1 - 1e-9
+	}
+	return 4 * d.count * q * (1 - q) / d.compression
+}
+
+// Add merges a single sample into the digest.
+func (d *TDigest) Add(x float64) {
+	d.count++
+
+	if len(d.centroids) == 0 {
+		d.centroids = append(d.centroids, tdigestCentroid{mean: x, weight: 1})
+		return
+	}
+
+	// Find the nearest centroid by mean. Among equally near centroids
+	// (repeated values), take the one with the most room left under its
+	// scale-function budget, so duplicates merge instead of piling up.
+	best, bestCum := 0, 0.0
+	bestDist := math.Abs(d.centroids[0].mean - x)
+	room := func(i int, cum float64) float64 {
+		return d.maxWeight((cum+d.centroids[i].weight/2)/d.count) - d.centroids[i].weight
+	}
+	for i, cum := 1, d.centroids[0].weight; i < len(d.centroids); i++ {
+		if dist := math.Abs(d.centroids[i].mean - x); dist < bestDist || (dist == bestDist && room(i, cum) > room(best, bestCum)) {
+			best, bestDist, bestCum = i, dist, cum
+		}
+		cum += d.centroids[i].weight
+	}
+
+	// Estimate the cumulative weight up to (and including) the candidate
+	// centroid, to evaluate the scale-function budget.
+	cum := 0.0
+	for i := 0; i < best; i++ {
+		cum += d.centroids[i].weight
+	}
+	q := (cum + d.centroids[best].weight/2) / d.count
+	budget := d.maxWeight(q)
+
+	if d.centroids[best].weight+1 <= budget {
+		c := &d.centroids[best]
+		c.mean += (x - c.mean) / (c.weight + 1)
+		c.weight++
+		return
+	}
+
+	// Budget exhausted: insert a new singleton centroid, keeping the
+	// slice sorted by mean. x can fall on either side of the nearest
+	// centroid, so the insertion point is best (shift right) when x is
+	// smaller, or best+1 (insert after) when x is larger or equal.
+	insertAt := best + 1
+	if x < d.centroids[best].mean {
+		insertAt = best
+	}
+	d.centroids = append(d.centroids, tdigestCentroid{})
+	copy(d.centroids[insertAt+1:], d.centroids[insertAt:])
+	d.centroids[insertAt] = tdigestCentroid{mean: x, weight: 1}
+}
+
+// Quantile estimates the value at cumulative fraction q (0..1) by
+// interpolating across centroid weights.
+func (d *TDigest) Quantile(q float64) float64 {
+	if len(d.centroids) == 0 {
+		return 0
+	}
+	if len(d.centroids) == 1 {
+		return d.centroids[0].mean
+	}
+
+	target := q * d.count
+	cum := 0.0
+	for i, c := range d.centroids {
+		next := cum + c.weight
+		if target <= next || i == len(d.centroids)-1 {
+			if i == 0 {
+				return c.mean
+			}
+			prev := d.centroids[i-1]
+			// Linear interpolation between the two centroid means,
+			// weighted by how far into this centroid's span we are.
+			span := next - cum
+			if span <= 0 {
+				return c.mean
+			}
+			frac := (target - cum) / span
+			return prev.mean + frac*(c.mean-prev.mean)
+		}
+		cum = next
+	}
+	return d.centroids[len(d.centroids)-1].mean
+}
+
+// OnlineStats accumulates count/mean/variance/min/max via Welford's
+// online algorithm and median/percentiles via a TDigest, so a full
+// dataset never needs to be held in memory or sorted.
+type OnlineStats struct {
+	count  int64
+	mean   float64
+	m2     float64
+	sum    float64
+	min    float64
+	max    float64
+	first  bool
+	digest *TDigest
+}
+
+// NewOnlineStats creates an accumulator whose percentile estimates use
+// the given t-digest compression (delta).
+func NewOnlineStats(delta float64) *OnlineStats {
+	return &OnlineStats{
+		first:  true,
+		digest: NewTDigest(delta),
+	}
+}
+
+// Add folds a single value into the running statistics. This is
+// Welford's algorithm: count++; delta = x-mean; mean += delta/count;
+// delta2 = x-mean; m2 += delta*delta2 — numerically stable in one pass,
+// unlike accumulating sum-of-squares directly.
+func (s *OnlineStats) Add(x float64) {
+	s.count++
+	s.sum += x
+	delta := x - s.mean
+	s.mean += delta / float64(s.count)
+	delta2 := x - s.mean
+	s.m2 += delta * delta2
+
+	if s.first {
+		s.min, s.max = x, x
+		s.first = false
+	} else {
+		if x < s.min {
+			s.min = x
+		}
+		if x > s.max {
+			s.max = x
+		}
+	}
+
+	s.digest.Add(x)
+}
+
+// Finalize computes the summary statistics from the accumulated state.
+func (s *OnlineStats) Finalize() map[string]interface{} {
+	if s.count == 0 {
+		return map[string]interface{}{"error": "empty dataset"}
+	}
+
+	variance := s.m2 / float64(s.count)
+
+	return map[string]interface{}{
+		"count":    s.count,
+		"sum":      s.sum,
+		"mean":     s.mean,
+		"std_dev":  math.Sqrt(variance),
+		"variance": variance,
+		"min":      s.min,
+		"max":      s.max,
+		"median":   s.digest.Quantile(0.5),
+		"p25":      s.digest.Quantile(0.25),
+		"p75":      s.digest.Quantile(0.75),
+		"p95":      s.digest.Quantile(0.95),
+		"p99":      s.digest.Quantile(0.99),
+		"range":    s.max - s.min,
+	}
+}